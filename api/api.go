@@ -1,8 +1,12 @@
 package api
 
-import(
+import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+
+	"go_tutorials/api/retry"
 )
 
 type CoinBalanceParams struct {
@@ -17,31 +21,145 @@ type CoinBalanceResponse struct {
 	Balance int64
 }
 
+// Error is the response body returned for every failed request. It also
+// backs RFC 7807 (application/problem+json) responses when the client asks
+// for them via the Accept header.
 type Error struct {
-	// Error code
+	// Code is the HTTP status code.
 	Code int
 
-	// Error message
+	// Message is a human-readable description of the error.
 	Message string
+
+	// Reason is a short, machine-readable identifier for the error class
+	// (e.g. "insufficient_funds"), stable across releases.
+	Reason string
+
+	// Type is a URI identifying the error class, suitable for the "type"
+	// member of a problem+json document. Defaults to "about:blank".
+	Type string
+
+	// RequestID identifies the request that produced the error, so it can
+	// be correlated with server-side logs.
+	RequestID string
+
+	// Details carries optional machine-readable context about the error.
+	Details map[string]interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
 }
 
-func writeError(w http.ResponseWriter, message string, code int) {
-	resp := Error {
-		Code: code,
+// New builds an *Error with the given HTTP status code, machine-readable
+// reason, and human-readable message.
+func New(code int, reason, message string) *Error {
+	return &Error{
+		Code:    code,
+		Reason:  reason,
 		Message: message,
+		Type:    "about:blank",
 	}
+}
 
-	w.Header().Set("Content-type", "application/json")
-	w.WriteHeader(code)
+// BadRequest builds a 400 Error with reason "bad_request".
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, "bad_request", message)
+}
+
+// NotFound builds a 404 Error with reason "not_found".
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, "not_found", message)
+}
+
+// Conflict builds a 409 Error with reason "conflict".
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, "conflict", message)
+}
+
+// Internal builds a 500 Error with reason "internal_error".
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, "internal_error", message)
+}
 
-	json.NewEncoder(w).Encode(resp)
+// WithDetails attaches machine-readable context to the error and returns it
+// for chaining.
+func (e *Error) WithDetails(details map[string]interface{}) *Error {
+	e.Details = details
+	return e
 }
 
+// problemJSON is the RFC 7807 wire format for application/problem+json.
+type problemJSON struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Reason   string                 `json:"reason,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+const problemContentType = "application/problem+json"
+
+func writeError(w http.ResponseWriter, r *http.Request, apiErr *Error) {
+	apiErr.RequestID = RequestIDFromContext(r.Context())
+
+	if r.Header.Get("Accept") == problemContentType {
+		body := problemJSON{
+			Type:     apiErr.Type,
+			Title:    apiErr.Reason,
+			Status:   apiErr.Code,
+			Detail:   apiErr.Message,
+			Instance: apiErr.RequestID,
+			Reason:   apiErr.Reason,
+			Details:  apiErr.Details,
+		}
+
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(apiErr.Code)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Code)
+	json.NewEncoder(w).Encode(apiErr)
+}
+
+// toAPIError coerces an arbitrary error into an *Error, preserving it as-is
+// when it already is one and defaulting to a bad-request otherwise.
+func toAPIError(err error) *Error {
+	if apiErr, ok := err.(*Error); ok {
+		return apiErr
+	}
+	return BadRequest(err.Error())
+}
+
+// Retryable is implemented by errors that indicate the caller should retry
+// the request after a backoff interval, rather than treat it as a
+// client-side failure.
+type Retryable interface {
+	Retry() bool
+}
+
+// retryPolicy is used to compute the Retry-After header for retryable
+// errors surfaced through RequestErrorHandler.
+var retryPolicy = retry.DefaultBackoffPolicy()
+
 var (
-	RequestErrorHandler = func (w http.ResponseWriter, err error) {
-		writeError(w, err.Error(), http.StatusBadRequest)
+	RequestErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		if retryable, ok := err.(Retryable); ok && retryable.Retry() {
+			after := retryPolicy.NextInterval(0)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(after.Seconds()))))
+			apiErr := toAPIError(err)
+			apiErr.Code = http.StatusServiceUnavailable
+			writeError(w, r, apiErr)
+			return
+		}
+		writeError(w, r, toAPIError(err))
 	}
-	InternalErrorHandler = func (w http.ResponseWriter) {
-		writeError(w, "An Unexpected error ocurred", http.StatusInternalServerError)
+	InternalErrorHandler = func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, Internal("An Unexpected error ocurred"))
 	}
-)
\ No newline at end of file
+)
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteErrorPlainJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	writeError(w, r, NotFound("account not found"))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got Error
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if got.Reason != "not_found" || got.Message != "account not found" {
+		t.Fatalf("body = %+v, want Reason=not_found Message=%q", got, "account not found")
+	}
+}
+
+func TestWriteErrorProblemJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", problemContentType)
+
+	writeError(w, r, Conflict("balance already locked"))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, problemContentType)
+	}
+
+	var got problemJSON
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if got.Title != "conflict" || got.Detail != "balance already locked" || got.Status != http.StatusConflict {
+		t.Fatalf("body = %+v, want Title=conflict Detail=%q Status=%d", got, "balance already locked", http.StatusConflict)
+	}
+}
+
+func TestWriteErrorIncludesRequestIDFromContext(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(WithRequestID(r.Context(), "req-123"))
+
+	writeError(w, r, BadRequest("bad input"))
+
+	var got Error
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if got.RequestID != "req-123" {
+		t.Fatalf("RequestID = %q, want req-123", got.RequestID)
+	}
+}
+
+func TestRequestErrorHandlerNonRetryableWritesTheErrorAsIs(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	RequestErrorHandler(w, r, NotFound("account not found"))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Fatalf("Retry-After = %q, want unset for a non-retryable error", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestInternalErrorHandlerWrites500(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	InternalErrorHandler(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
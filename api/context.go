@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// RequestIDHeader is the header used to propagate a request ID to and from
+// the client.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestIDFromContext returns the request ID stored in ctx by
+// WithRequestID, or the empty string if none is present.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func newRequestID() string {
+	return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+}
+
+// RequestIDMiddleware ensures every request carries a request ID: it reuses
+// the incoming X-Request-Id header when present, otherwise generates a new
+// ULID. The ID is stored in the request context (so RequestErrorHandler and
+// InternalErrorHandler can attach it to error responses) and echoed back on
+// the response.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		r = r.WithContext(WithRequestID(r.Context(), id))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddlewareReusesIncomingHeader(t *testing.T) {
+	var gotFromContext string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFromContext = RequestIDFromContext(r.Context())
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(RequestIDHeader, "client-provided-id")
+	w := httptest.NewRecorder()
+
+	RequestIDMiddleware(next).ServeHTTP(w, r)
+
+	if gotFromContext != "client-provided-id" {
+		t.Fatalf("request ID in context = %q, want client-provided-id", gotFromContext)
+	}
+	if got := w.Header().Get(RequestIDHeader); got != "client-provided-id" {
+		t.Fatalf("response header %s = %q, want client-provided-id", RequestIDHeader, got)
+	}
+}
+
+func TestRequestIDMiddlewareGeneratesIDWhenAbsent(t *testing.T) {
+	var gotFromContext string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFromContext = RequestIDFromContext(r.Context())
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	RequestIDMiddleware(next).ServeHTTP(w, r)
+
+	if gotFromContext == "" {
+		t.Fatal("request ID in context is empty, want a generated ULID")
+	}
+	if got := w.Header().Get(RequestIDHeader); got != gotFromContext {
+		t.Fatalf("response header %s = %q, want it to match the context value %q", RequestIDHeader, got, gotFromContext)
+	}
+}
+
+func TestRequestIDMiddlewareGeneratesDistinctIDsPerRequest(t *testing.T) {
+	var ids []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, RequestIDFromContext(r.Context()))
+	})
+
+	handler := RequestIDMiddleware(next)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if ids[0] == ids[1] {
+		t.Fatalf("generated the same request ID twice: %q", ids[0])
+	}
+}
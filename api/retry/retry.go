@@ -0,0 +1,120 @@
+// Package retry implements a generic exponential backoff policy with
+// jitter, used to retry transient failures such as upstream coin-balance
+// lookups or DB calls.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffPolicy configures the exponential backoff used by Retry.
+type BackoffPolicy struct {
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the computed delay between retries.
+	MaxInterval time.Duration
+
+	// Multiplier grows the interval on each attempt (interval * Multiplier^n).
+	Multiplier float64
+
+	// RandomizationFactor jitters the interval by +/- this fraction.
+	RandomizationFactor float64
+
+	// MaxElapsedTime aborts retrying when waiting for the next interval
+	// would push the total time past this limit. Zero means no limit.
+	MaxElapsedTime time.Duration
+
+	// MaxRetries caps the number of attempts after the first one. Zero means
+	// no limit.
+	MaxRetries int
+}
+
+// DefaultBackoffPolicy returns a sensible policy for retrying transient
+// upstream failures.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{
+		InitialInterval:     500 * time.Millisecond,
+		MaxInterval:         30 * time.Second,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+		MaxElapsedTime:      2 * time.Minute,
+		MaxRetries:          5,
+	}
+}
+
+// PermanentError wraps an error to signal that it must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+func (p *PermanentError) Error() string { return p.Err.Error() }
+func (p *PermanentError) Unwrap() error { return p.Err }
+
+// Permanent wraps err so that Retry stops immediately instead of retrying it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// NextInterval returns the (jittered) delay to use before attempt n, where
+// attempt 0 is the delay before the first retry.
+func (p BackoffPolicy) NextInterval(n int) time.Duration {
+	interval := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(n))
+	if max := float64(p.MaxInterval); max > 0 && interval > max {
+		interval = max
+	}
+
+	if p.RandomizationFactor <= 0 {
+		return time.Duration(interval)
+	}
+
+	delta := p.RandomizationFactor * interval
+	min := interval - delta
+	max := interval + delta
+	return time.Duration(min + rand.Float64()*(max-min))
+}
+
+// Retry invokes op until it returns nil, op returns an error wrapped with
+// Permanent, ctx is cancelled, or policy's MaxRetries/MaxElapsedTime is
+// exceeded. It returns ctx.Err() when ctx is cancelled, the unwrapped error
+// for a Permanent error, and otherwise the last error op returned.
+func Retry(ctx context.Context, op func() error, policy BackoffPolicy) error {
+	start := time.Now()
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		lastErr = op()
+		if lastErr == nil {
+			return nil
+		}
+
+		var permanent *PermanentError
+		if errors.As(lastErr, &permanent) {
+			return permanent.Err
+		}
+
+		if policy.MaxRetries > 0 && attempt >= policy.MaxRetries {
+			return lastErr
+		}
+
+		interval := policy.NextInterval(attempt)
+		if policy.MaxElapsedTime > 0 && time.Since(start)+interval > policy.MaxElapsedTime {
+			return lastErr
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextIntervalGrowsAndCaps(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     100 * time.Millisecond,
+		MaxInterval:         300 * time.Millisecond,
+		Multiplier:          2,
+		RandomizationFactor: 0,
+	}
+
+	if got := policy.NextInterval(0); got != 100*time.Millisecond {
+		t.Fatalf("NextInterval(0) = %v, want 100ms", got)
+	}
+	if got := policy.NextInterval(1); got != 200*time.Millisecond {
+		t.Fatalf("NextInterval(1) = %v, want 200ms", got)
+	}
+	if got := policy.NextInterval(2); got != 300*time.Millisecond {
+		t.Fatalf("NextInterval(2) = %v, want 300ms (capped)", got)
+	}
+}
+
+func TestNextIntervalJitterStaysInRange(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     100 * time.Millisecond,
+		MaxInterval:         time.Second,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+	}
+
+	for i := 0; i < 100; i++ {
+		got := policy.NextInterval(0)
+		if got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("NextInterval(0) = %v, want within [50ms, 150ms]", got)
+		}
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     time.Millisecond,
+		MaxInterval:         10 * time.Millisecond,
+		Multiplier:          2,
+		RandomizationFactor: 0,
+		MaxRetries:          5,
+	}
+
+	attempts := 0
+	err := Retry(context.Background(), func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, policy)
+
+	if err != nil {
+		t.Fatalf("Retry() = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	policy := DefaultBackoffPolicy()
+
+	attempts := 0
+	permanentErr := errors.New("do not retry me")
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return Permanent(permanentErr)
+	}, policy)
+
+	if !errors.Is(err, permanentErr) {
+		t.Fatalf("Retry() = %v, want %v", err, permanentErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryStopsAtMaxRetries(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     time.Millisecond,
+		MaxInterval:         time.Millisecond,
+		Multiplier:          1,
+		RandomizationFactor: 0,
+		MaxRetries:          2,
+	}
+
+	attempts := 0
+	wantErr := errors.New("still failing")
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return wantErr
+	}, policy)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Retry() = %v, want %v", err, wantErr)
+	}
+	if attempts != 3 { // first attempt + 2 retries
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryRespectsContextCancellation(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     time.Hour,
+		MaxInterval:         time.Hour,
+		Multiplier:          1,
+		RandomizationFactor: 0,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Retry(ctx, func() error {
+			attempts++
+			return errors.New("always fails")
+		}, policy)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Retry() = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Retry did not return promptly after cancellation")
+	}
+
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryStopsAtMaxElapsedTime(t *testing.T) {
+	policy := BackoffPolicy{
+		InitialInterval:     20 * time.Millisecond,
+		MaxInterval:         20 * time.Millisecond,
+		Multiplier:          1,
+		RandomizationFactor: 0,
+		MaxElapsedTime:      15 * time.Millisecond,
+	}
+
+	attempts := 0
+	wantErr := errors.New("still failing")
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return wantErr
+	}, policy)
+
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Retry() = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (next interval already exceeds MaxElapsedTime)", attempts)
+	}
+}
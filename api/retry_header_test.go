@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type retryableError struct{ error }
+
+func (retryableError) Retry() bool { return true }
+
+func TestRequestErrorHandlerSetsNonZeroRetryAfter(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	RequestErrorHandler(w, r, retryableError{errors.New("upstream unavailable")})
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	header := w.Header().Get("Retry-After")
+	seconds, err := strconv.Atoi(header)
+	if err != nil {
+		t.Fatalf("Retry-After = %q, want a plain integer: %v", header, err)
+	}
+	if seconds < 1 {
+		t.Fatalf("Retry-After = %d, want >= 1 (a retryable error must never tell clients to retry immediately)", seconds)
+	}
+}
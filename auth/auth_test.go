@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func requireMachineID(t *testing.T) string {
+	t.Helper()
+	id, err := MachineID()
+	if err != nil {
+		t.Skipf("MachineID() unavailable in this environment: %v", err)
+	}
+	return id
+}
+
+func TestDeriveAppKeyDependsOnBothInputs(t *testing.T) {
+	k1 := deriveAppKey("machine-a", "app-1")
+	k2 := deriveAppKey("machine-a", "app-2")
+	k3 := deriveAppKey("machine-b", "app-1")
+
+	if bytes.Equal(k1, k2) {
+		t.Fatal("deriveAppKey produced the same key for different app IDs")
+	}
+	if bytes.Equal(k1, k3) {
+		t.Fatal("deriveAppKey produced the same key for different machine IDs")
+	}
+}
+
+func TestCanonicalRequestIsDeterministic(t *testing.T) {
+	a := canonicalRequest("POST", "/account/alice/transfer", "1700000000", []byte(`{"to":"bob"}`))
+	b := canonicalRequest("POST", "/account/alice/transfer", "1700000000", []byte(`{"to":"bob"}`))
+	if a != b {
+		t.Fatalf("canonicalRequest is not deterministic: %q != %q", a, b)
+	}
+
+	c := canonicalRequest("POST", "/account/alice/transfer", "1700000000", []byte(`{"to":"carol"}`))
+	if a == c {
+		t.Fatal("canonicalRequest did not change when the body changed")
+	}
+}
+
+func TestSignRequestVerifyRoundTrip(t *testing.T) {
+	machineID := requireMachineID(t)
+
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": machineID})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	body := []byte(`{"to":"bob","amount":10}`)
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/account/alice/transfer", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "app-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestVerifyRejectsUnknownAppID(t *testing.T) {
+	requireMachineID(t)
+
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": "some-other-machine"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/alice/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "unregistered-app"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestVerifyRejectsWrongMachineID(t *testing.T) {
+	requireMachineID(t)
+
+	// Registry holds a different machine ID than the one SignRequest will
+	// actually use, so the derived HMAC can never match.
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": "not-the-real-machine-id"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/alice/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "app-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestVerifyRejectsTamperedSignature(t *testing.T) {
+	machineID := requireMachineID(t)
+
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": machineID})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/alice/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "app-1"); err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Signature", "00")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestVerifyRejectsClockSkew(t *testing.T) {
+	machineID := requireMachineID(t)
+
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": machineID})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/alice/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SignRequest(req, "app-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the timestamp to be far outside the allowed skew. The
+	// signature no longer matches this timestamp either way, so this also
+	// exercises that Verify checks skew before (or regardless of) the HMAC.
+	staleTimestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	req.Header.Set("X-Timestamp", staleTimestamp)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestVerifyRejectsMissingHeaders(t *testing.T) {
+	srv := httptest.NewServer(Verify(map[string]string{"app-1": "whatever"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/account/alice/balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+}
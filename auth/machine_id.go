@@ -0,0 +1,25 @@
+// Package auth signs outbound API requests with an HMAC derived from the
+// machine they originate from, and verifies those signatures on the server
+// side against a registry of known per-app machine IDs.
+package auth
+
+import "sync"
+
+var (
+	machineIDOnce   sync.Once
+	cachedMachineID string
+	cachedErr       error
+)
+
+// MachineID returns a stable identifier for the machine the process is
+// running on, read once per process and cached thereafter.
+//
+// The raw value is a sensitive, long-lived identifier: callers must never
+// log it or return it to a client. Only the HMAC-derived values produced by
+// SignRequest and Verify are safe to expose.
+func MachineID() (string, error) {
+	machineIDOnce.Do(func() {
+		cachedMachineID, cachedErr = platformMachineID()
+	})
+	return cachedMachineID, cachedErr
+}
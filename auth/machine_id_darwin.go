@@ -0,0 +1,25 @@
+//go:build darwin
+
+package auth
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+var darwinUUIDPattern = regexp.MustCompile(`"IOPlatformUUID" = "([^"]+)"`)
+
+func platformMachineID() (string, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", fmt.Errorf("auth: reading machine id via ioreg: %w", err)
+	}
+
+	match := darwinUUIDPattern.FindSubmatch(out)
+	if match == nil {
+		return "", fmt.Errorf("auth: IOPlatformUUID not found in ioreg output")
+	}
+
+	return string(match[1]), nil
+}
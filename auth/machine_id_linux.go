@@ -0,0 +1,31 @@
+//go:build linux
+
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// linuxMachineIDPaths are tried in order; the first readable one wins.
+var linuxMachineIDPaths = []string{
+	"/etc/machine-id",
+	"/var/lib/dbus/machine-id",
+}
+
+func platformMachineID() (string, error) {
+	for _, path := range linuxMachineIDPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		id := strings.TrimSpace(string(data))
+		if id != "" {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("auth: no machine id found (tried %s)", strings.Join(linuxMachineIDPaths, ", "))
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go_tutorials/api"
+)
+
+// maxClockSkew bounds how far a request's X-Timestamp may drift from the
+// server's clock before it is rejected, in either direction.
+const maxClockSkew = 5 * time.Minute
+
+// Verify returns middleware that authenticates requests signed by
+// SignRequest. registry maps an app ID to the machine ID that was
+// registered for it out of band (e.g. during app provisioning); requests
+// signed by any other machine are rejected even if the app ID is known.
+func Verify(registry map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			appID := r.Header.Get("X-App-Id")
+			timestampHeader := r.Header.Get("X-Timestamp")
+			signature := r.Header.Get("X-Signature")
+
+			if appID == "" || timestampHeader == "" || signature == "" {
+				api.RequestErrorHandler(w, r, unauthorized("missing signature headers"))
+				return
+			}
+
+			machineID, ok := registry[appID]
+			if !ok {
+				api.RequestErrorHandler(w, r, unauthorized("unknown app id"))
+				return
+			}
+
+			timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+			if err != nil {
+				api.RequestErrorHandler(w, r, api.BadRequest("invalid timestamp"))
+				return
+			}
+
+			skew := time.Since(time.Unix(timestamp, 0))
+			if skew > maxClockSkew || skew < -maxClockSkew {
+				api.RequestErrorHandler(w, r, unauthorized("request timestamp outside allowed skew"))
+				return
+			}
+
+			body, err := readAndRestoreBody(r)
+			if err != nil {
+				api.InternalErrorHandler(w, r)
+				return
+			}
+
+			canonical := canonicalRequest(r.Method, r.URL.Path, timestampHeader, body)
+			mac := hmac.New(sha256.New, deriveAppKey(machineID, appID))
+			mac.Write([]byte(canonical))
+			expected := mac.Sum(nil)
+
+			got, err := hex.DecodeString(signature)
+			if err != nil || !hmac.Equal(got, expected) {
+				api.RequestErrorHandler(w, r, unauthorized("signature mismatch"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(message string) *api.Error {
+	return api.New(http.StatusUnauthorized, "unauthorized", message)
+}
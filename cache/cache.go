@@ -0,0 +1,234 @@
+// Package cache implements a sharded, in-process LRU cache with per-entry
+// TTL, suitable for high-throughput lookups such as coin balances.
+package cache
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// entry is one node of a shard's intrusive doubly linked LRU list.
+type entry struct {
+	hash   uint64
+	key    []byte
+	value  []byte
+	expiry time.Time
+	prev   *entry
+	next   *entry
+}
+
+// shard is an independently locked LRU segment of the Cache. Keys route to
+// a shard by hash to keep lock contention local to one segment.
+type shard struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[uint64]*entry
+	head     *entry // most recently used
+	tail     *entry // least recently used
+}
+
+func newShard(capacity int) *shard {
+	return &shard{
+		capacity: capacity,
+		items:    make(map[uint64]*entry, capacity),
+	}
+}
+
+// Cache is a sharded LRU cache keyed on arbitrary byte slices, with entries
+// expiring after a fixed TTL.
+type Cache struct {
+	shards []*shard
+	ttl    time.Duration
+	done   chan struct{}
+	closed sync.Once
+}
+
+// New returns a Cache with the given number of shards, each holding up to
+// capacityPerShard entries, with entries expiring after ttl. Call Close when
+// the Cache is no longer needed to stop its background sweep goroutines.
+func New(shards, capacityPerShard int, ttl time.Duration) *Cache {
+	c := &Cache{
+		shards: make([]*shard, shards),
+		ttl:    ttl,
+		done:   make(chan struct{}),
+	}
+	for i := range c.shards {
+		c.shards[i] = newShard(capacityPerShard)
+	}
+
+	interval := ttl / 4
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for _, s := range c.shards {
+		go sweepLoop(s, interval, c.done)
+	}
+
+	return c
+}
+
+// Close stops the background goroutines that sweep expired entries. It is
+// safe to call more than once; the Cache must not be used afterwards.
+func (c *Cache) Close() {
+	c.closed.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *Cache) shardFor(key []byte) (*shard, uint64) {
+	h := xxhash.Sum64(key)
+	return c.shards[h%uint64(len(c.shards))], h
+}
+
+// Get returns the cached value for key, or (nil, false) if it is absent or
+// expired.
+func (c *Cache) Get(key []byte) ([]byte, bool) {
+	s, h := c.shardFor(key)
+	return s.get(h, key)
+}
+
+// Set stores value under key, evicting the least recently used entry in its
+// shard if that pushes the shard over capacity.
+func (c *Cache) Set(key, value []byte) {
+	s, h := c.shardFor(key)
+	s.set(h, key, value, time.Now().Add(c.ttl))
+}
+
+// Invalidate removes key from the cache, if present.
+func (c *Cache) Invalidate(key []byte) {
+	s, h := c.shardFor(key)
+	s.invalidate(h, key)
+}
+
+func (s *shard) get(hash uint64, key []byte) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[hash]
+	if !ok || !bytes.Equal(e.key, key) {
+		return nil, false
+	}
+	if time.Now().After(e.expiry) {
+		s.removeEntry(e)
+		return nil, false
+	}
+
+	s.moveToFront(e)
+	return e.value, true
+}
+
+func (s *shard) set(hash uint64, key, value []byte, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.items[hash]; ok && bytes.Equal(e.key, key) {
+		e.value = value
+		e.expiry = expiry
+		s.moveToFront(e)
+		return
+	} else if ok {
+		// Two distinct keys hash to the same value: evict the old one
+		// instead of silently overwriting its entry in place.
+		s.removeEntry(e)
+	}
+
+	e := &entry{hash: hash, key: key, value: value, expiry: expiry}
+	s.items[hash] = e
+	s.pushFront(e)
+
+	if len(s.items) > s.capacity {
+		s.evictLRU()
+	}
+}
+
+func (s *shard) invalidate(hash uint64, key []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[hash]
+	if !ok || !bytes.Equal(e.key, key) {
+		return
+	}
+	s.removeEntry(e)
+}
+
+func (s *shard) sweepExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for hash, e := range s.items {
+		if now.After(e.expiry) {
+			s.unlink(e)
+			delete(s.items, hash)
+		}
+	}
+}
+
+func sweepLoop(s *shard, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.sweepExpired()
+		}
+	}
+}
+
+// pushFront inserts e as the most recently used entry. Callers must hold
+// s.mu.
+func (s *shard) pushFront(e *entry) {
+	e.prev = nil
+	e.next = s.head
+	if s.head != nil {
+		s.head.prev = e
+	}
+	s.head = e
+	if s.tail == nil {
+		s.tail = e
+	}
+}
+
+// unlink removes e from the list without deleting it from s.items. Callers
+// must hold s.mu.
+func (s *shard) unlink(e *entry) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		s.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		s.tail = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+func (s *shard) moveToFront(e *entry) {
+	if s.head == e {
+		return
+	}
+	s.unlink(e)
+	s.pushFront(e)
+}
+
+// removeEntry evicts e from both the list and the map. Callers must hold
+// s.mu.
+func (s *shard) removeEntry(e *entry) {
+	s.unlink(e)
+	delete(s.items, e.hash)
+}
+
+func (s *shard) evictLRU() {
+	if s.tail != nil {
+		s.removeEntry(s.tail)
+	}
+}
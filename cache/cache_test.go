@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCacheSetGet(t *testing.T) {
+	c := New(1, 4, time.Minute)
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+
+	v, ok := c.Get([]byte("a"))
+	if !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = (%q, %v), want (1, true)", v, ok)
+	}
+}
+
+func TestCacheGetMissing(t *testing.T) {
+	c := New(1, 4, time.Minute)
+	defer c.Close()
+
+	if _, ok := c.Get([]byte("missing")); ok {
+		t.Fatal("Get(missing) = true, want false")
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c := New(1, 4, time.Minute)
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+	c.Invalidate([]byte("a"))
+
+	if _, ok := c.Get([]byte("a")); ok {
+		t.Fatal("Get(a) after Invalidate = true, want false")
+	}
+}
+
+func TestCacheExpiresAfterTTL(t *testing.T) {
+	c := New(1, 4, 30*time.Millisecond)
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := c.Get([]byte("a")); ok {
+		t.Fatal("Get(a) after TTL expiry = true, want false")
+	}
+}
+
+func TestCacheBackgroundSweepRemovesExpiredEntries(t *testing.T) {
+	c := New(1, 4, 20*time.Millisecond) // sweep interval = ttl/4 = 5ms
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+
+	s := c.shards[0]
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		n := len(s.items)
+		s.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expired entry was not swept by the background goroutine within 1s")
+}
+
+// TestCacheEvictsLRU uses a single shard/capacity-1 cache so eviction order
+// is deterministic regardless of how xxhash distributes keys.
+func TestCacheEvictsLRU(t *testing.T) {
+	c := New(1, 1, time.Minute)
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+	c.Set([]byte("b"), []byte("2")) // evicts "a", the only entry
+
+	if _, ok := c.Get([]byte("a")); ok {
+		t.Fatal("Get(a) after eviction = true, want false")
+	}
+	if v, ok := c.Get([]byte("b")); !ok || string(v) != "2" {
+		t.Fatalf("Get(b) = (%q, %v), want (2, true)", v, ok)
+	}
+}
+
+func TestCacheMoveToFrontProtectsRecentlyUsedEntry(t *testing.T) {
+	c := New(1, 2, time.Minute)
+	defer c.Close()
+
+	c.Set([]byte("a"), []byte("1"))
+	c.Set([]byte("b"), []byte("2"))
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	c.Get([]byte("a"))
+	c.Set([]byte("c"), []byte("3"))
+
+	if _, ok := c.Get([]byte("b")); ok {
+		t.Fatal("Get(b) after it became LRU = true, want false (should have been evicted)")
+	}
+	if _, ok := c.Get([]byte("a")); !ok {
+		t.Fatal("Get(a) after being touched = false, want true (should have survived eviction)")
+	}
+}
+
+// TestCacheHashCollisionIsHandledByEqualityCheck forces two distinct keys
+// into the same map slot of a single shard by calling shard.set directly
+// with the same hash for both, verifying set's equality check evicts the
+// older key rather than corrupting it.
+func TestCacheHashCollisionIsHandledByEqualityCheck(t *testing.T) {
+	c := New(1, 4, time.Minute)
+	defer c.Close()
+
+	s := c.shards[0]
+
+	// Manually insert two entries that share a hash but have different
+	// keys, bypassing shardFor's real xxhash routing to simulate a
+	// collision deterministically.
+	const sharedHash = uint64(42)
+	s.set(sharedHash, []byte("key-one"), []byte("value-one"), time.Now().Add(time.Minute))
+	s.set(sharedHash, []byte("key-two"), []byte("value-two"), time.Now().Add(time.Minute))
+
+	if v, ok := s.get(sharedHash, []byte("key-one")); ok {
+		t.Fatalf("get(key-one) after collision = (%q, true), want a miss (evicted by key-two)", v)
+	}
+	v, ok := s.get(sharedHash, []byte("key-two"))
+	if !ok || string(v) != "value-two" {
+		t.Fatalf("get(key-two) after collision = (%q, %v), want (value-two, true)", v, ok)
+	}
+}
+
+func TestCacheCloseStopsGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	shards := 4
+	c := New(shards, 4, 10*time.Millisecond)
+	for i := 0; i < shards; i++ {
+		c.Set([]byte(strconv.Itoa(i)), []byte("v"))
+	}
+
+	c.Close()
+	c.Close() // must be safe to call twice
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= before+1 { // +1 for test/runtime slack
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count did not return to baseline after Close: before=%d, after=%d", before, runtime.NumGoroutine())
+}
@@ -0,0 +1,132 @@
+package coinbalance
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"go_tutorials/api"
+	"go_tutorials/auth"
+	"go_tutorials/cache"
+)
+
+// Handler exposes a Store over HTTP.
+type Handler struct {
+	Store Store
+
+	// Cache, when set, is consulted before GetBalance hits Store and is
+	// invalidated for every account touched by Transfer.
+	Cache *cache.Cache
+
+	// AuthRegistry, when set, requires every request to carry a valid
+	// auth.SignRequest signature (see auth.Verify) and maps the signing
+	// app's X-App-Id to its registered machine ID.
+	AuthRegistry map[string]string
+}
+
+// NewHandler returns a Handler serving balances and transfers out of store.
+func NewHandler(store Store) *Handler {
+	return &Handler{Store: store}
+}
+
+// NewCachedHandler returns a Handler that also caches balance lookups in c.
+func NewCachedHandler(store Store, c *cache.Cache) *Handler {
+	return &Handler{Store: store, Cache: c}
+}
+
+// Routes registers the coin-balance endpoints on r. When AuthRegistry is
+// set, the endpoints are gated behind auth.Verify.
+func (h *Handler) Routes(r chi.Router) {
+	if h.AuthRegistry != nil {
+		r = r.With(auth.Verify(h.AuthRegistry))
+	}
+
+	r.Get("/account/{username}/balance", h.GetBalance)
+	r.Post("/account/{username}/transfer", h.Transfer)
+}
+
+// GetBalance handles GET /account/{username}/balance.
+func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+
+	if h.Cache != nil {
+		if cached, ok := h.Cache.Get([]byte(username)); ok {
+			writeJSON(w, api.CoinBalanceResponse{Code: http.StatusOK, Balance: decodeBalance(cached)})
+			return
+		}
+	}
+
+	balance, err := h.Store.GetBalance(r.Context(), username)
+	if err != nil {
+		api.RequestErrorHandler(w, r, toAPIError(err))
+		return
+	}
+
+	if h.Cache != nil {
+		h.Cache.Set([]byte(username), encodeBalance(balance))
+	}
+
+	writeJSON(w, api.CoinBalanceResponse{Code: http.StatusOK, Balance: balance})
+}
+
+func encodeBalance(balance int64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(balance))
+	return buf
+}
+
+func decodeBalance(buf []byte) int64 {
+	return int64(binary.BigEndian.Uint64(buf))
+}
+
+type transferRequest struct {
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// Transfer handles POST /account/{username}/transfer with a JSON body of
+// {"to": "...", "amount": ...}.
+func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
+	from := chi.URLParam(r, "username")
+
+	var body transferRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		api.RequestErrorHandler(w, r, api.BadRequest("invalid request body"))
+		return
+	}
+
+	if err := h.Store.Transfer(r.Context(), from, body.To, body.Amount); err != nil {
+		api.RequestErrorHandler(w, r, toAPIError(err))
+		return
+	}
+
+	if h.Cache != nil {
+		h.Cache.Invalidate([]byte(from))
+		h.Cache.Invalidate([]byte(body.To))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// toAPIError maps coinbalance sentinel errors onto the api package's
+// structured error model; unrecognised errors pass through unchanged, and
+// RequestErrorHandler reports them as a 400 bad request.
+func toAPIError(err error) error {
+	switch {
+	case errors.Is(err, ErrInsufficientFunds):
+		return api.Conflict(err.Error())
+	case errors.Is(err, ErrInvalidAmount):
+		return api.BadRequest(err.Error())
+	default:
+		return err
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,144 @@
+package coinbalance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"go_tutorials/api"
+	"go_tutorials/auth"
+	"go_tutorials/cache"
+)
+
+func newTestServer(t *testing.T, h *Handler) *httptest.Server {
+	t.Helper()
+	r := chi.NewRouter()
+	h.Routes(r)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHandlerGetBalance(t *testing.T) {
+	store := NewInMemoryStore()
+	store.Credit(context.Background(), "alice", 100)
+
+	srv := newTestServer(t, NewHandler(store))
+
+	resp, err := http.Get(srv.URL + "/account/alice/balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var body api.CoinBalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Balance != 100 {
+		t.Fatalf("balance = %d, want 100", body.Balance)
+	}
+}
+
+func TestHandlerTransferInsufficientFunds(t *testing.T) {
+	store := NewInMemoryStore()
+	store.Credit(context.Background(), "alice", 10)
+
+	srv := newTestServer(t, NewHandler(store))
+
+	resp, err := http.Post(srv.URL+"/account/alice/transfer", "application/json", strings.NewReader(`{"to":"bob","amount":50}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", resp.StatusCode)
+	}
+}
+
+func TestHandlerRoutesRequireAuthWhenRegistrySet(t *testing.T) {
+	machineID, err := auth.MachineID()
+	if err != nil {
+		t.Skipf("auth.MachineID() unavailable in this environment: %v", err)
+	}
+
+	store := NewInMemoryStore()
+	store.Credit(context.Background(), "alice", 100)
+
+	h := NewHandler(store)
+	h.AuthRegistry = map[string]string{"test-app": machineID}
+	srv := newTestServer(t, h)
+
+	resp, err := http.Get(srv.URL + "/account/alice/balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unsigned request status = %d, want 401", resp.StatusCode)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/alice/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.SignRequest(req, "test-app"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("signed request status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestHandlerTransferInvalidatesCache(t *testing.T) {
+	store := NewInMemoryStore()
+	store.Credit(context.Background(), "alice", 100)
+
+	c := cache.New(2, 16, time.Minute)
+	srv := newTestServer(t, NewCachedHandler(store, c))
+
+	// Warm the cache.
+	if _, err := http.Get(srv.URL + "/account/alice/balance"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(srv.URL+"/account/alice/transfer", "application/json", strings.NewReader(`{"to":"bob","amount":30}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("transfer status = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/account/alice/balance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body api.CoinBalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Balance != 70 {
+		t.Fatalf("balance after transfer = %d, want 70 (stale cache not invalidated)", body.Balance)
+	}
+}
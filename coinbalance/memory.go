@@ -0,0 +1,71 @@
+package coinbalance
+
+import (
+	"context"
+	"sync"
+)
+
+// InMemoryStore is a Store backed by a map guarded by a single RWMutex.
+// It is suitable for tests and local development, not for multi-process
+// deployments.
+type InMemoryStore struct {
+	mu       sync.RWMutex
+	balances map[string]int64
+}
+
+// NewInMemoryStore returns an empty InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{balances: make(map[string]int64)}
+}
+
+func (s *InMemoryStore) GetBalance(ctx context.Context, username string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.balances[username], nil
+}
+
+func (s *InMemoryStore) Credit(ctx context.Context, username string, amount int64) (int64, error) {
+	if amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balances[username] += amount
+	return s.balances[username], nil
+}
+
+func (s *InMemoryStore) Debit(ctx context.Context, username string, amount int64) (int64, error) {
+	if amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.balances[username] < amount {
+		return 0, ErrInsufficientFunds
+	}
+
+	s.balances[username] -= amount
+	return s.balances[username], nil
+}
+
+func (s *InMemoryStore) Transfer(ctx context.Context, from, to string, amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.balances[from] < amount {
+		return ErrInsufficientFunds
+	}
+
+	s.balances[from] -= amount
+	s.balances[to] += amount
+	return nil
+}
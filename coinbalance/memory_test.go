@@ -0,0 +1,120 @@
+package coinbalance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInMemoryStoreCreditDebit(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+
+	if balance, err := s.Credit(ctx, "alice", 100); err != nil || balance != 100 {
+		t.Fatalf("Credit() = (%d, %v), want (100, nil)", balance, err)
+	}
+
+	if balance, err := s.Debit(ctx, "alice", 40); err != nil || balance != 60 {
+		t.Fatalf("Debit() = (%d, %v), want (60, nil)", balance, err)
+	}
+
+	if balance, err := s.GetBalance(ctx, "alice"); err != nil || balance != 60 {
+		t.Fatalf("GetBalance() = (%d, %v), want (60, nil)", balance, err)
+	}
+}
+
+func TestInMemoryStoreGetBalanceMissingAccountIsZero(t *testing.T) {
+	s := NewInMemoryStore()
+
+	balance, err := s.GetBalance(context.Background(), "nobody")
+	if err != nil || balance != 0 {
+		t.Fatalf("GetBalance() = (%d, %v), want (0, nil)", balance, err)
+	}
+}
+
+func TestInMemoryStoreDebitInsufficientFunds(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	s.Credit(ctx, "alice", 10)
+
+	_, err := s.Debit(ctx, "alice", 20)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Debit() err = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestInMemoryStoreCreditDebitRejectNonPositiveAmount(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+
+	if _, err := s.Credit(ctx, "alice", 0); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("Credit(0) err = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := s.Debit(ctx, "alice", -5); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("Debit(-5) err = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestInMemoryStoreTransfer(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	s.Credit(ctx, "alice", 100)
+
+	if err := s.Transfer(ctx, "alice", "bob", 30); err != nil {
+		t.Fatalf("Transfer() = %v, want nil", err)
+	}
+
+	aliceBalance, _ := s.GetBalance(ctx, "alice")
+	bobBalance, _ := s.GetBalance(ctx, "bob")
+	if aliceBalance != 70 || bobBalance != 30 {
+		t.Fatalf("balances after transfer = (alice=%d, bob=%d), want (70, 30)", aliceBalance, bobBalance)
+	}
+}
+
+func TestInMemoryStoreTransferSelfWithInsufficientFundsFails(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	s.Credit(ctx, "alice", 10)
+
+	err := s.Transfer(ctx, "alice", "alice", 1000)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Transfer(alice, alice, 1000) err = %v, want ErrInsufficientFunds", err)
+	}
+
+	balance, _ := s.GetBalance(ctx, "alice")
+	if balance != 10 {
+		t.Fatalf("balance after failed self-transfer = %d, want unchanged 10", balance)
+	}
+}
+
+func TestInMemoryStoreTransferSelfIsNoOpWhenSufficient(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	s.Credit(ctx, "alice", 10)
+
+	if err := s.Transfer(ctx, "alice", "alice", 5); err != nil {
+		t.Fatalf("Transfer(alice, alice, 5) = %v, want nil", err)
+	}
+
+	balance, _ := s.GetBalance(ctx, "alice")
+	if balance != 10 {
+		t.Fatalf("balance after self-transfer = %d, want unchanged 10", balance)
+	}
+}
+
+func TestInMemoryStoreTransferInsufficientFunds(t *testing.T) {
+	s := NewInMemoryStore()
+	ctx := context.Background()
+	s.Credit(ctx, "alice", 10)
+
+	err := s.Transfer(ctx, "alice", "bob", 20)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Transfer() err = %v, want ErrInsufficientFunds", err)
+	}
+
+	aliceBalance, _ := s.GetBalance(ctx, "alice")
+	bobBalance, _ := s.GetBalance(ctx, "bob")
+	if aliceBalance != 10 || bobBalance != 0 {
+		t.Fatalf("balances after failed transfer = (alice=%d, bob=%d), want unchanged (10, 0)", aliceBalance, bobBalance)
+	}
+}
@@ -0,0 +1,153 @@
+package coinbalance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"go_tutorials/api/retry"
+)
+
+// Postgres error codes this package cares about.
+const (
+	pgErrCodeCheckViolation       = "23514"
+	pgErrCodeSerializationFailure = "40001"
+)
+
+// transferBackoffPolicy bounds how Transfer retries a serialization failure:
+// short, tightly-capped backoff, since a serialization conflict is expected
+// to clear within milliseconds under normal contention.
+var transferBackoffPolicy = retry.BackoffPolicy{
+	InitialInterval:     5 * time.Millisecond,
+	MaxInterval:         100 * time.Millisecond,
+	Multiplier:          2,
+	RandomizationFactor: 0.5,
+	MaxRetries:          4,
+}
+
+// PGStore is a Store backed by Postgres, using the schema:
+//
+//	CREATE TABLE accounts (
+//		username TEXT PRIMARY KEY,
+//		balance  BIGINT NOT NULL CHECK (balance >= 0)
+//	);
+type PGStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGStore returns a PGStore backed by pool.
+func NewPGStore(pool *pgxpool.Pool) *PGStore {
+	return &PGStore{pool: pool}
+}
+
+func (s *PGStore) GetBalance(ctx context.Context, username string) (int64, error) {
+	var balance int64
+	err := s.pool.QueryRow(ctx, `SELECT balance FROM accounts WHERE username = $1`, username).Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (s *PGStore) Credit(ctx context.Context, username string, amount int64) (int64, error) {
+	if amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	var balance int64
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO accounts (username, balance) VALUES ($1, $2)
+		ON CONFLICT (username) DO UPDATE SET balance = accounts.balance + EXCLUDED.balance
+		RETURNING balance`, username, amount).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (s *PGStore) Debit(ctx context.Context, username string, amount int64) (int64, error) {
+	if amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	var balance int64
+	err := s.pool.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $2 WHERE username = $1
+		RETURNING balance`, username, amount).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInsufficientFunds(err) {
+			return 0, ErrInsufficientFunds
+		}
+		return 0, err
+	}
+	return balance, nil
+}
+
+// Transfer moves amount from one account to another inside a serializable
+// transaction, retrying with backoff when Postgres reports a serialization
+// failure (40001) caused by concurrent transfers touching the same
+// accounts.
+func (s *PGStore) Transfer(ctx context.Context, from, to string, amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	err := retry.Retry(ctx, func() error {
+		err := s.transferOnce(ctx, from, to, amount)
+		if err != nil && !isSerializationFailure(err) {
+			return retry.Permanent(err)
+		}
+		return err
+	}, transferBackoffPolicy)
+
+	if err != nil && isSerializationFailure(err) {
+		return fmt.Errorf("coinbalance: transfer from %q to %q did not converge after repeated serialization conflicts: %w", from, to, err)
+	}
+	return err
+}
+
+func (s *PGStore) transferOnce(ctx context.Context, from, to string, amount int64) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var fromBalance int64
+	err = tx.QueryRow(ctx, `
+		UPDATE accounts SET balance = balance - $2 WHERE username = $1
+		RETURNING balance`, from, amount).Scan(&fromBalance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInsufficientFunds(err) {
+			return ErrInsufficientFunds
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO accounts (username, balance) VALUES ($1, $2)
+		ON CONFLICT (username) DO UPDATE SET balance = accounts.balance + EXCLUDED.balance`, to, amount)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func isInsufficientFunds(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgErrCodeCheckViolation
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgErrCodeSerializationFailure
+}
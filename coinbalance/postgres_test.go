@@ -0,0 +1,80 @@
+package coinbalance
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func TestIsInsufficientFunds(t *testing.T) {
+	checkViolation := &pgconn.PgError{Code: pgErrCodeCheckViolation}
+	if !isInsufficientFunds(checkViolation) {
+		t.Fatalf("isInsufficientFunds(%v) = false, want true", checkViolation)
+	}
+
+	other := &pgconn.PgError{Code: pgErrCodeSerializationFailure}
+	if isInsufficientFunds(other) {
+		t.Fatalf("isInsufficientFunds(%v) = true, want false", other)
+	}
+
+	if isInsufficientFunds(errors.New("not a pg error")) {
+		t.Fatal("isInsufficientFunds(non-pg error) = true, want false")
+	}
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	serializationFailure := &pgconn.PgError{Code: pgErrCodeSerializationFailure}
+	if !isSerializationFailure(serializationFailure) {
+		t.Fatalf("isSerializationFailure(%v) = false, want true", serializationFailure)
+	}
+
+	other := &pgconn.PgError{Code: pgErrCodeCheckViolation}
+	if isSerializationFailure(other) {
+		t.Fatalf("isSerializationFailure(%v) = true, want false", other)
+	}
+}
+
+// TestPGStoreTransferSelfWithInsufficientFundsFails locks in that transferring
+// an account to itself is not special-cased: it goes through the same debit
+// query as any other transfer and relies on the accounts.balance CHECK
+// constraint to reject it, matching InMemoryStore's behavior. It requires a
+// real Postgres reachable via DATABASE_URL (unavailable in this sandbox) and
+// is skipped otherwise.
+func TestPGStoreTransferSelfWithInsufficientFundsFails(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set; skipping test that requires a live Postgres instance")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool.Connect: %v", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.Exec(ctx, `
+		CREATE TEMP TABLE accounts (
+			username TEXT PRIMARY KEY,
+			balance  BIGINT NOT NULL CHECK (balance >= 0)
+		)`); err != nil {
+		t.Fatalf("create temp table: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO accounts (username, balance) VALUES ('alice', 10)`); err != nil {
+		t.Fatalf("seed account: %v", err)
+	}
+
+	s := NewPGStore(pool)
+	if err := s.Transfer(ctx, "alice", "alice", 1000); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Transfer(alice, alice, 1000) err = %v, want ErrInsufficientFunds", err)
+	}
+
+	balance, err := s.GetBalance(ctx, "alice")
+	if err != nil || balance != 10 {
+		t.Fatalf("balance after failed self-transfer = (%d, %v), want (10, nil)", balance, err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package coinbalance persists account balances behind a pluggable Store,
+// with an in-memory implementation for tests/local development and a
+// Postgres-backed implementation for production.
+package coinbalance
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInsufficientFunds is returned by Debit and Transfer when the source
+// account does not hold enough balance to cover the requested amount.
+var ErrInsufficientFunds = errors.New("coinbalance: insufficient funds")
+
+// ErrInvalidAmount is returned when a Credit, Debit, or Transfer amount is
+// not strictly positive.
+var ErrInvalidAmount = errors.New("coinbalance: amount must be positive")
+
+// Store persists and mutates account balances. A missing account is
+// treated as having a zero balance rather than as an error.
+type Store interface {
+	// GetBalance returns the current balance for username.
+	GetBalance(ctx context.Context, username string) (int64, error)
+
+	// Credit adds amount to username's balance and returns the new balance.
+	Credit(ctx context.Context, username string, amount int64) (int64, error)
+
+	// Debit subtracts amount from username's balance and returns the new
+	// balance. It fails with ErrInsufficientFunds rather than let the
+	// balance go negative.
+	Debit(ctx context.Context, username string, amount int64) (int64, error)
+
+	// Transfer atomically moves amount from one account to another. It
+	// fails with ErrInsufficientFunds if from cannot cover amount.
+	Transfer(ctx context.Context, from, to string, amount int64) error
+}